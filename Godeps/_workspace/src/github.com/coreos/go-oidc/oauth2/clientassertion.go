@@ -0,0 +1,151 @@
+package oauth2
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// assertionTTL is the lifetime given to a client_assertion JWT. RFC 7523
+// recommends keeping this short, since a leaked assertion is replayable
+// until it expires.
+const assertionTTL = 5 * time.Minute
+
+// buildClientAssertion builds and signs the JWT sent as client_assertion
+// for client_secret_jwt (HMAC of the client secret, RFC 7523 section 2.2)
+// and private_key_jwt (RSA/EC signature with c.cfg.SigningKey, section
+// 2.1). Per section 3, iss and sub are both the client_id, aud is the
+// token endpoint, and jti/iat/exp guard against replay.
+func (c *Client) buildClientAssertion() (string, error) {
+	now := time.Now().UTC()
+
+	jti, err := randomJTI()
+	if err != nil {
+		return "", err
+	}
+
+	claims := map[string]interface{}{
+		"iss": c.cfg.Credentials.ID,
+		"sub": c.cfg.Credentials.ID,
+		"aud": c.cfg.TokenURL,
+		"jti": jti,
+		"iat": now.Unix(),
+		"exp": now.Add(assertionTTL).Unix(),
+	}
+
+	switch c.cfg.AuthMethod {
+	case AuthMethodClientSecretJWT:
+		return c.signHMAC(claims)
+	case AuthMethodPrivateKeyJWT:
+		return c.signWithKey(claims)
+	default:
+		return "", fmt.Errorf("oauth2: %q cannot build a client assertion", c.cfg.AuthMethod)
+	}
+}
+
+func (c *Client) signHMAC(claims map[string]interface{}) (string, error) {
+	if c.cfg.Credentials.Secret == "" {
+		return "", errors.New("oauth2: client_secret_jwt requires a client secret")
+	}
+
+	signingInput, err := encodeJWTSigningInput("HS256", c.cfg.SigningKeyID, claims)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, []byte(c.cfg.Credentials.Secret))
+	mac.Write([]byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (c *Client) signWithKey(claims map[string]interface{}) (string, error) {
+	if c.cfg.SigningKey == nil {
+		return "", errors.New("oauth2: private_key_jwt requires a SigningKey")
+	}
+
+	ecKey, isEC := c.cfg.SigningKey.Public().(*ecdsa.PublicKey)
+
+	alg := c.cfg.SigningAlgorithm
+	if alg == "" {
+		if isEC {
+			alg = "ES256"
+		} else {
+			alg = "RS256"
+		}
+	}
+
+	signingInput, err := encodeJWTSigningInput(alg, c.cfg.SigningKeyID, claims)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256([]byte(signingInput))
+
+	sig, err := c.cfg.SigningKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("signing client assertion: %v", err)
+	}
+
+	if isEC {
+		// crypto.Signer.Sign returns an ASN.1 DER ECDSA signature, but JWS
+		// (RFC 7518 section 3.4) requires the fixed-width r||s encoding.
+		sig, err = asn1ECDSAToJWS(sig, ecKey.Curve.Params().BitSize)
+		if err != nil {
+			return "", fmt.Errorf("encoding ECDSA client assertion signature: %v", err)
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// asn1ECDSAToJWS converts an ASN.1 DER ECDSA signature, as produced by
+// crypto/ecdsa, into the fixed-width r||s encoding JWS expects.
+func asn1ECDSAToJWS(der []byte, curveBits int) ([]byte, error) {
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, err
+	}
+
+	size := (curveBits + 7) / 8
+	out := make([]byte, 2*size)
+	sig.R.FillBytes(out[:size])
+	sig.S.FillBytes(out[size:])
+
+	return out, nil
+}
+
+func encodeJWTSigningInput(alg, kid string, claims map[string]interface{}) (string, error) {
+	header := map[string]interface{}{"typ": "JWT", "alg": alg}
+	if kid != "" {
+		header["kid"] = kid
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON), nil
+}
+
+func randomJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,105 @@
+package oauth2
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeClaims(t *testing.T, jwt string) map[string]interface{} {
+	t.Helper()
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("want a 3-part JWT, got %d parts: %s", len(parts), jwt)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decoding claims segment: %v", err)
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatalf("unmarshaling claims: %v", err)
+	}
+
+	return claims
+}
+
+func TestBuildClientAssertionClientSecretJWT(t *testing.T) {
+	c := &Client{cfg: Config{
+		Credentials: ClientCredentials{ID: "client-a", Secret: "s3cr3t"},
+		TokenURL:    "https://idp.example.com/token",
+		AuthMethod:  AuthMethodClientSecretJWT,
+	}}
+
+	assertion, err := c.buildClientAssertion()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims := decodeClaims(t, assertion)
+	for _, want := range []string{"iss", "sub"} {
+		if claims[want] != "client-a" {
+			t.Errorf("%s: want %q, got %q", want, "client-a", claims[want])
+		}
+	}
+	if claims["aud"] != "https://idp.example.com/token" {
+		t.Errorf("aud: got %v", claims["aud"])
+	}
+	if claims["jti"] == "" || claims["jti"] == nil {
+		t.Error("jti: want a non-empty value")
+	}
+}
+
+func TestBuildClientAssertionClientSecretJWTRequiresSecret(t *testing.T) {
+	c := &Client{cfg: Config{
+		Credentials: ClientCredentials{ID: "client-a"},
+		TokenURL:    "https://idp.example.com/token",
+		AuthMethod:  AuthMethodClientSecretJWT,
+	}}
+
+	if _, err := c.buildClientAssertion(); err == nil {
+		t.Error("expected an error with no client secret configured")
+	}
+}
+
+func TestBuildClientAssertionPrivateKeyJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating test key: %v", err)
+	}
+
+	c := &Client{cfg: Config{
+		Credentials: ClientCredentials{ID: "client-b"},
+		TokenURL:    "https://idp.example.com/token",
+		AuthMethod:  AuthMethodPrivateKeyJWT,
+		SigningKey:  key,
+	}}
+
+	assertion, err := c.buildClientAssertion()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims := decodeClaims(t, assertion)
+	if claims["iss"] != "client-b" {
+		t.Errorf("iss: got %v", claims["iss"])
+	}
+}
+
+func TestBuildClientAssertionPrivateKeyJWTRequiresSigningKey(t *testing.T) {
+	c := &Client{cfg: Config{
+		Credentials: ClientCredentials{ID: "client-b"},
+		TokenURL:    "https://idp.example.com/token",
+		AuthMethod:  AuthMethodPrivateKeyJWT,
+	}}
+
+	if _, err := c.buildClientAssertion(); err == nil {
+		t.Error("expected an error with no signing key configured")
+	}
+}
@@ -0,0 +1,253 @@
+// Package oauth2 implements the OAuth2 client used by the oidc package to
+// talk to a provider's authorization and token endpoints.
+package oauth2
+
+import (
+	"crypto"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	phttp "github.com/coreos/go-oidc/http"
+)
+
+const (
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodClientSecretJWT   = "client_secret_jwt"
+	AuthMethodPrivateKeyJWT     = "private_key_jwt"
+	AuthMethodTLSClientAuth     = "tls_client_auth"
+	AuthMethodNone              = "none"
+
+	GrantTypeAuthCode     = "authorization_code"
+	GrantTypeClientCreds  = "client_credentials"
+	GrantTypeRefreshToken = "refresh_token"
+
+	// assertionTypeJWTBearer is the client_assertion_type value required
+	// by RFC 7523 section 2.2 for both private_key_jwt and
+	// client_secret_jwt.
+	assertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// ClientCredentials holds a client's identifier and, for auth methods that
+// need one, its shared secret. Secret is empty for "none" (public clients
+// using PKCE) and unused for "private_key_jwt"/"tls_client_auth", which
+// authenticate with SigningKey/TLSClientConfig instead.
+type ClientCredentials struct {
+	ID     string
+	Secret string
+}
+
+// Param is an extra, provider- or flow-specific parameter to attach to a
+// token or authorization request, such as PKCE's code_verifier or OIDC's
+// nonce.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Config describes how to reach a provider's OAuth2 endpoints and how this
+// client should authenticate itself to them.
+type Config struct {
+	Credentials ClientCredentials
+	RedirectURL string
+	AuthURL     string
+	TokenURL    string
+	Scope       []string
+	AuthMethod  string
+
+	// SigningKey, SigningKeyID and SigningAlgorithm configure
+	// private_key_jwt (RFC 7523 section 2.1): the client signs its own
+	// assertion with an RSA or EC private key the provider has the public
+	// half of. SigningAlgorithm defaults to RS256 for an *rsa.PrivateKey
+	// and ES256 for an *ecdsa.PrivateKey when empty.
+	SigningKey       crypto.Signer
+	SigningKeyID     string
+	SigningAlgorithm string
+
+	// TLSClientConfig authenticates the client via mutual TLS
+	// (tls_client_auth) instead of a secret or signed assertion.
+	TLSClientConfig *tls.Config
+}
+
+// Client requests and refreshes tokens against a single provider's
+// endpoints, authenticating with the method chosen in Config.AuthMethod.
+type Client struct {
+	hc  phttp.Client
+	cfg Config
+}
+
+// NewClient returns a Client that issues requests with hc, falling back
+// to an mTLS-capable client built from cfg.TLSClientConfig when
+// cfg.AuthMethod is "tls_client_auth" and hc is nil.
+func NewClient(hc phttp.Client, cfg Config) (*Client, error) {
+	if cfg.AuthURL == "" || cfg.TokenURL == "" {
+		return nil, errors.New("oauth2: missing auth or token URL")
+	}
+
+	if hc == nil {
+		if cfg.AuthMethod == AuthMethodTLSClientAuth && cfg.TLSClientConfig != nil {
+			hc = &http.Client{Transport: &http.Transport{TLSClientConfig: cfg.TLSClientConfig}}
+		} else {
+			hc = http.DefaultClient
+		}
+	}
+
+	return &Client{hc: hc, cfg: cfg}, nil
+}
+
+// AuthCodeURL returns the URL to redirect a user to the provider's
+// authorization endpoint, with state and any extra params (a PKCE
+// code_challenge, an OIDC nonce, ...) attached alongside the standard
+// OAuth2 parameters.
+func (c *Client) AuthCodeURL(state string, params ...Param) string {
+	v := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.cfg.Credentials.ID},
+		"scope":         {strings.Join(c.cfg.Scope, " ")},
+		"state":         {state},
+	}
+	if c.cfg.RedirectURL != "" {
+		v.Set("redirect_uri", c.cfg.RedirectURL)
+	}
+	for _, p := range params {
+		v.Set(p.Key, p.Value)
+	}
+
+	u := c.cfg.AuthURL
+	if strings.Contains(u, "?") {
+		u += "&" + v.Encode()
+	} else {
+		u += "?" + v.Encode()
+	}
+	return u
+}
+
+// TokenResponse is the decoded JSON body of a successful token endpoint
+// response.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// ClientCredsToken requests a token using the client_credentials grant.
+func (c *Client) ClientCredsToken(scope []string) (TokenResponse, error) {
+	v := url.Values{}
+	if len(scope) > 0 {
+		v.Set("scope", strings.Join(scope, " "))
+	}
+	return c.requestToken(GrantTypeClientCreds, v)
+}
+
+// RequestToken requests a token using grantType, where value is the
+// grant's primary credential (the auth code, the refresh token, or empty
+// for client_credentials), plus any extra params such as PKCE's
+// code_verifier or an audience restriction.
+func (c *Client) RequestToken(grantType, value string, params ...Param) (TokenResponse, error) {
+	v := url.Values{}
+
+	switch grantType {
+	case GrantTypeAuthCode:
+		v.Set("code", value)
+		if c.cfg.RedirectURL != "" {
+			v.Set("redirect_uri", c.cfg.RedirectURL)
+		}
+	case GrantTypeRefreshToken:
+		v.Set("refresh_token", value)
+	}
+
+	for _, p := range params {
+		v.Set(p.Key, p.Value)
+	}
+
+	return c.requestToken(grantType, v)
+}
+
+func (c *Client) requestToken(grantType string, v url.Values) (TokenResponse, error) {
+	v.Set("grant_type", grantType)
+
+	resp, err := c.authenticatedPostForm(c.cfg.TokenURL, v)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return TokenResponse{}, fmt.Errorf("oauth2: error response from token endpoint: %s: %s", resp.Status, body)
+	}
+
+	var t TokenResponse
+	if err := json.Unmarshal(body, &t); err != nil {
+		return TokenResponse{}, err
+	}
+
+	return t, nil
+}
+
+// AuthenticatedPostForm POSTs v, form-encoded, to endpoint, authenticating
+// the client the same way requestToken does. It backs oidc.Client's
+// introspection and revocation calls, which hit a different endpoint but
+// authenticate identically to a token request.
+func (c *Client) AuthenticatedPostForm(endpoint string, v url.Values) (*http.Response, error) {
+	return c.authenticatedPostForm(endpoint, v)
+}
+
+func (c *Client) authenticatedPostForm(endpoint string, v url.Values) (*http.Response, error) {
+	if err := c.authenticate(&v); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if c.cfg.AuthMethod == AuthMethodClientSecretBasic {
+		req.SetBasicAuth(url.QueryEscape(c.cfg.Credentials.ID), url.QueryEscape(c.cfg.Credentials.Secret))
+	}
+
+	return c.hc.Do(req)
+}
+
+// authenticate mutates v to carry whatever credential c.cfg.AuthMethod
+// requires. client_secret_basic is handled separately as an Authorization
+// header by the caller, since it must not also appear in the form body.
+func (c *Client) authenticate(v *url.Values) error {
+	switch c.cfg.AuthMethod {
+	case AuthMethodClientSecretBasic:
+		// handled via Authorization header by the caller
+	case AuthMethodClientSecretPost:
+		v.Set("client_id", c.cfg.Credentials.ID)
+		v.Set("client_secret", c.cfg.Credentials.Secret)
+	case AuthMethodNone:
+		v.Set("client_id", c.cfg.Credentials.ID)
+	case AuthMethodClientSecretJWT, AuthMethodPrivateKeyJWT:
+		assertion, err := c.buildClientAssertion()
+		if err != nil {
+			return fmt.Errorf("oauth2: building client assertion: %v", err)
+		}
+		v.Set("client_id", c.cfg.Credentials.ID)
+		v.Set("client_assertion_type", assertionTypeJWTBearer)
+		v.Set("client_assertion", assertion)
+	case AuthMethodTLSClientAuth:
+		v.Set("client_id", c.cfg.Credentials.ID)
+	default:
+		return fmt.Errorf("oauth2: unsupported auth method %q", c.cfg.AuthMethod)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package oauth2
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// recordingHTTPClient captures the last request it was asked to perform
+// and answers every call with a canned token response.
+type recordingHTTPClient struct {
+	lastReq *http.Request
+	lastURL url.Values
+}
+
+func (c *recordingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.lastReq = req
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.lastURL, err = url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id_token":"x"}`))),
+	}, nil
+}
+
+func TestRequestTokenClientCredsSendsScopeAndAudience(t *testing.T) {
+	hc := &recordingHTTPClient{}
+	c, err := NewClient(hc, Config{
+		Credentials: ClientCredentials{ID: "client-a", Secret: "s3cr3t"},
+		AuthURL:     "https://idp.example.com/auth",
+		TokenURL:    "https://idp.example.com/token",
+		AuthMethod:  AuthMethodClientSecretPost,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = c.RequestToken(GrantTypeClientCreds, "",
+		Param{Key: "audience", Value: "https://api.example.com"},
+		Param{Key: "scope", Value: "read write"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hc.lastURL.Get("audience"); got != "https://api.example.com" {
+		t.Errorf("audience = %q, want %q", got, "https://api.example.com")
+	}
+	if got := hc.lastURL.Get("scope"); got != "read write" {
+		t.Errorf("scope = %q, want %q", got, "read write")
+	}
+	if got := hc.lastURL.Get("grant_type"); got != GrantTypeClientCreds {
+		t.Errorf("grant_type = %q, want %q", got, GrantTypeClientCreds)
+	}
+}
+
+func TestAuthenticateClientSecretPostAddsCredentialsToBody(t *testing.T) {
+	c := &Client{cfg: Config{
+		Credentials: ClientCredentials{ID: "client-a", Secret: "s3cr3t"},
+		AuthMethod:  AuthMethodClientSecretPost,
+	}}
+
+	v := url.Values{}
+	if err := c.authenticate(&v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v.Get("client_id") != "client-a" || v.Get("client_secret") != "s3cr3t" {
+		t.Errorf("want client_id/client_secret in body, got %v", v)
+	}
+}
+
+func TestAuthCodeURLIncludesState(t *testing.T) {
+	c := &Client{cfg: Config{
+		Credentials: ClientCredentials{ID: "client-a"},
+		AuthURL:     "https://idp.example.com/auth",
+		Scope:       []string{"openid", "email"},
+	}}
+
+	got := c.AuthCodeURL("the-state", Param{Key: "nonce", Value: "the-nonce"})
+	if !strings.Contains(got, "state=the-state") {
+		t.Errorf("want state in auth URL, got %s", got)
+	}
+	if !strings.Contains(got, "nonce=the-nonce") {
+		t.Errorf("want nonce in auth URL, got %s", got)
+	}
+}
+
+func TestNewClientBuildsMTLSTransportForTLSClientAuth(t *testing.T) {
+	tlsCfg := &tls.Config{ServerName: "idp.example.com"}
+
+	c, err := NewClient(nil, Config{
+		Credentials:     ClientCredentials{ID: "client-a"},
+		AuthURL:         "https://idp.example.com/auth",
+		TokenURL:        "https://idp.example.com/token",
+		AuthMethod:      AuthMethodTLSClientAuth,
+		TLSClientConfig: tlsCfg,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hc, ok := c.hc.(*http.Client)
+	if !ok {
+		t.Fatalf("want an *http.Client, got %T", c.hc)
+	}
+	tr, ok := hc.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("want an *http.Transport, got %T", hc.Transport)
+	}
+	if tr.TLSClientConfig != tlsCfg {
+		t.Error("want the configured TLSClientConfig wired into the transport")
+	}
+}
+
+func TestNewClientDefaultsToDefaultClientWithoutTLSClientAuth(t *testing.T) {
+	c, err := NewClient(nil, Config{
+		Credentials: ClientCredentials{ID: "client-a"},
+		AuthURL:     "https://idp.example.com/auth",
+		TokenURL:    "https://idp.example.com/token",
+		AuthMethod:  AuthMethodClientSecretPost,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.hc != http.DefaultClient {
+		t.Error("want http.DefaultClient when tls_client_auth is not in use")
+	}
+}
@@ -1,10 +1,13 @@
 package oidc
 
 import (
+	"crypto"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +29,10 @@ var (
 	supportedAuthMethods = map[string]struct{}{
 		oauth2.AuthMethodClientSecretBasic: struct{}{},
 		oauth2.AuthMethodClientSecretPost:  struct{}{},
+		oauth2.AuthMethodPrivateKeyJWT:     struct{}{},
+		oauth2.AuthMethodClientSecretJWT:   struct{}{},
+		oauth2.AuthMethodTLSClientAuth:     struct{}{},
+		oauth2.AuthMethodNone:              struct{}{},
 	}
 )
 
@@ -63,6 +70,26 @@ type ClientConfig struct {
 	RedirectURL    string
 	ProviderConfig ProviderConfig
 	KeySet         key.PublicKeySet
+
+	// SigningKey, when set, lets the client authenticate to the token
+	// endpoint with private_key_jwt (RFC 7523 section 2.1) instead of
+	// sending its client secret. SigningKeyID and SigningAlgorithm are
+	// copied into the assertion's "kid" header and "alg", respectively;
+	// SigningAlgorithm defaults to ES256 for an ECDSA key and RS256 for
+	// any other key if empty.
+	SigningKey       crypto.Signer
+	SigningKeyID     string
+	SigningAlgorithm string
+
+	// TLSClientConfig, when set, authenticates the client via mutual TLS
+	// (tls_client_auth) instead of a shared secret or signed assertion.
+	TLSClientConfig *tls.Config
+
+	// PublicKeyRepo, when set, is used to sync JWKS instead of the
+	// default NewRemotePublicKeyRepo fetch from ProviderConfig's
+	// KeysEndpoint. Set this to load keys from disk or another source in
+	// environments where the IdP's JWKS endpoint is not reachable.
+	PublicKeyRepo key.PublicKeyRepo
 }
 
 func NewClient(cfg ClientConfig) (*Client, error) {
@@ -74,12 +101,17 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 	}
 
 	c := Client{
-		credentials:    cfg.Credentials,
-		httpClient:     cfg.HTTPClient,
-		scope:          cfg.Scope,
-		redirectURL:    ru.String(),
-		providerConfig: newProviderConfigRepo(cfg.ProviderConfig),
-		keySet:         cfg.KeySet,
+		credentials:      cfg.Credentials,
+		httpClient:       cfg.HTTPClient,
+		scope:            cfg.Scope,
+		redirectURL:      ru.String(),
+		providerConfig:   newProviderConfigRepo(cfg.ProviderConfig),
+		keySet:           cfg.KeySet,
+		signingKey:       cfg.SigningKey,
+		signingKeyID:     cfg.SigningKeyID,
+		signingAlgorithm: cfg.SigningAlgorithm,
+		tlsClientConfig:  cfg.TLSClientConfig,
+		keyRepo:          cfg.PublicKeyRepo,
 	}
 
 	if c.httpClient == nil {
@@ -103,6 +135,12 @@ type Client struct {
 	keySet         key.PublicKeySet
 	providerSyncer *ProviderConfigSyncer
 
+	signingKey       crypto.Signer
+	signingKeyID     string
+	signingAlgorithm string
+	tlsClientConfig  *tls.Config
+	keyRepo          key.PublicKeyRepo
+
 	keySetSyncMutex sync.RWMutex
 	lastKeySetSync  time.Time
 }
@@ -123,42 +161,121 @@ func (c *Client) Healthy() error {
 	return nil
 }
 
+// AuthCodeURL returns a URL that directs a user to the provider's
+// authorization endpoint, attaching state and any extra params (such as a
+// PKCE code_challenge or an OIDC nonce) alongside the standard OAuth2
+// parameters.
+func (c *Client) AuthCodeURL(state string, params ...oauth2.Param) (string, error) {
+	oac, err := c.OAuthClient()
+	if err != nil {
+		return "", err
+	}
+	return oac.AuthCodeURL(state, params...), nil
+}
+
 func (c *Client) OAuthClient() (*oauth2.Client, error) {
 	cfg := c.providerConfig.Get()
-	authMethod, err := chooseAuthMethod(cfg)
+	authMethod, err := c.chooseAuthMethod(cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	hc := c.httpClient
+	if authMethod == oauth2.AuthMethodTLSClientAuth && c.tlsClientConfig != nil {
+		// c.httpClient is never nil by this point (NewClient defaults it
+		// to http.DefaultClient), so oauth2.NewClient's own hc == nil
+		// fallback for tls_client_auth never fires. Build the mTLS-aware
+		// client directly here instead.
+		hc = &http.Client{Transport: &http.Transport{TLSClientConfig: c.tlsClientConfig}}
+	}
+
 	ocfg := oauth2.Config{
-		Credentials: oauth2.ClientCredentials(c.credentials),
-		RedirectURL: c.redirectURL,
-		AuthURL:     cfg.AuthEndpoint,
-		TokenURL:    cfg.TokenEndpoint,
-		Scope:       c.scope,
-		AuthMethod:  authMethod,
+		Credentials:      oauth2.ClientCredentials(c.credentials),
+		RedirectURL:      c.redirectURL,
+		AuthURL:          cfg.AuthEndpoint,
+		TokenURL:         cfg.TokenEndpoint,
+		Scope:            c.scope,
+		AuthMethod:       authMethod,
+		SigningKey:       c.signingKey,
+		SigningKeyID:     c.signingKeyID,
+		SigningAlgorithm: c.signingAlgorithm,
+		TLSClientConfig:  c.tlsClientConfig,
 	}
 
-	return oauth2.NewClient(c.httpClient, ocfg)
+	return oauth2.NewClient(hc, ocfg)
+}
+
+// authMethodPreference ranks auth methods from strongest to weakest so
+// chooseAuthMethod picks deliberately rather than taking whichever
+// provider-supported method happens to come first in the provider's own,
+// arbitrarily-ordered list. private_key_jwt and tls_client_auth, which
+// never put a shared secret on the wire, rank above the secret-based
+// methods; "none" is last since it offers no client authentication at all.
+var authMethodPreference = []string{
+	oauth2.AuthMethodPrivateKeyJWT,
+	oauth2.AuthMethodTLSClientAuth,
+	oauth2.AuthMethodClientSecretJWT,
+	oauth2.AuthMethodClientSecretPost,
+	oauth2.AuthMethodClientSecretBasic,
+	oauth2.AuthMethodNone,
 }
 
-func chooseAuthMethod(cfg ProviderConfig) (string, error) {
+// chooseAuthMethod picks the strongest method from authMethodPreference
+// that the provider advertises, this package supports, and c is actually
+// configured for: private_key_jwt and tls_client_auth are only chosen when
+// c has a SigningKey or TLSClientConfig to back them, so a client with
+// neither configured falls through to a secret-based method even if the
+// provider lists the stronger ones first.
+func (c *Client) chooseAuthMethod(cfg ProviderConfig) (string, error) {
+	// Per the OIDC discovery spec, a provider that omits
+	// token_endpoint_auth_methods_supported entirely is assumed to only
+	// support the OAuth2 default, client_secret_basic.
 	if len(cfg.TokenEndpointAuthMethodsSupported) == 0 {
 		return oauth2.AuthMethodClientSecretBasic, nil
 	}
 
-	for _, authMethod := range cfg.TokenEndpointAuthMethodsSupported {
-		if _, ok := supportedAuthMethods[authMethod]; ok {
-			return authMethod, nil
+	supported := make(map[string]struct{}, len(cfg.TokenEndpointAuthMethodsSupported))
+	for _, m := range cfg.TokenEndpointAuthMethodsSupported {
+		if _, ok := supportedAuthMethods[m]; ok {
+			supported[m] = struct{}{}
+		}
+	}
+
+	for _, authMethod := range authMethodPreference {
+		if _, ok := supported[authMethod]; !ok {
+			continue
+		}
+
+		switch authMethod {
+		case oauth2.AuthMethodPrivateKeyJWT:
+			if c.signingKey == nil {
+				continue
+			}
+		case oauth2.AuthMethodTLSClientAuth:
+			if c.tlsClientConfig == nil {
+				continue
+			}
 		}
+
+		return authMethod, nil
 	}
 
 	return "", errors.New("no supported auth methods")
 }
 
-// SyncProviderConfig starts the provider config syncer
+// SyncProviderConfig starts the provider config syncer using the
+// provider's HTTP discovery document. Use SyncProviderConfigFromSource to
+// sync from a different ProviderConfigGetter, such as one backed by a file
+// on disk or a static, pre-fetched config, for environments where the IdP
+// is not reachable at startup.
 func (c *Client) SyncProviderConfig(discoveryURL string) chan struct{} {
-	r := NewHTTPProviderConfigGetter(c.httpClient, discoveryURL)
+	return c.SyncProviderConfigFromSource(NewHTTPProviderConfigGetter(c.httpClient, discoveryURL))
+}
+
+// SyncProviderConfigFromSource starts the provider config syncer using r
+// as the source of provider config, polling it according to its reported
+// ExpiresAt the same way SyncProviderConfig does for the HTTP source.
+func (c *Client) SyncProviderConfigFromSource(r ProviderConfigGetter) chan struct{} {
 	s := NewProviderConfigSyncer(r, c.providerConfig)
 	stop := s.Run()
 	s.WaitUntilInitialSync()
@@ -185,8 +302,11 @@ func (c *Client) maybeSyncKeys() error {
 		return nil
 	}
 
-	cfg := c.providerConfig.Get()
-	r := NewRemotePublicKeyRepo(c.httpClient, cfg.KeysEndpoint)
+	r := c.keyRepo
+	if r == nil {
+		cfg := c.providerConfig.Get()
+		r = NewRemotePublicKeyRepo(c.httpClient, cfg.KeysEndpoint)
+	}
 	w := &clientKeyRepo{client: c}
 	_, err := key.Sync(r, w)
 	c.lastKeySetSync = time.Now().UTC()
@@ -208,6 +328,15 @@ func (r *clientKeyRepo) Set(ks key.KeySet) error {
 }
 
 func (c *Client) ClientCredsToken(scope []string) (jose.JWT, error) {
+	return c.ClientCredsTokenWithAudience(scope, "")
+}
+
+// ClientCredsTokenWithAudience is identical to ClientCredsToken, but also
+// sends the given audience value with the token request. Providers that
+// issue tokens scoped to a specific resource server (M2M callers such as CI
+// or signing tools) require this to get back a token that server will
+// accept.
+func (c *Client) ClientCredsTokenWithAudience(scope []string, audience string) (jose.JWT, error) {
 	cfg := c.providerConfig.Get()
 
 	if !cfg.SupportsGrantType(oauth2.GrantTypeClientCreds) {
@@ -219,7 +348,16 @@ func (c *Client) ClientCredsToken(scope []string) (jose.JWT, error) {
 		return jose.JWT{}, err
 	}
 
-	t, err := oac.ClientCredsToken(scope)
+	var t oauth2.TokenResponse
+	if audience == "" {
+		t, err = oac.ClientCredsToken(scope)
+	} else {
+		params := []oauth2.Param{{Key: "audience", Value: audience}}
+		if len(scope) > 0 {
+			params = append(params, oauth2.Param{Key: "scope", Value: strings.Join(scope, " ")})
+		}
+		t, err = oac.RequestToken(oauth2.GrantTypeClientCreds, "", params...)
+	}
 	if err != nil {
 		return jose.JWT{}, err
 	}
@@ -0,0 +1,71 @@
+package oidc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// fakeHTTPClient answers every request with a canned response and records
+// the endpoint and form body it was called with.
+type fakeHTTPClient struct {
+	status int
+	body   string
+
+	lastURL  string
+	lastForm url.Values
+}
+
+func (c *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.lastURL = req.URL.String()
+
+	b, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if c.lastForm, err = url.ParseQuery(string(b)); err != nil {
+		return nil, err
+	}
+
+	status := c.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return &http.Response{
+		Status:     http.StatusText(status),
+		StatusCode: status,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(c.body))),
+	}, nil
+}
+
+func newTestClient(t *testing.T, hc *fakeHTTPClient, cfg ProviderConfig) *Client {
+	t.Helper()
+	c, err := NewClient(ClientConfig{
+		HTTPClient:     hc,
+		Credentials:    ClientCredentials{ID: "client-a", Secret: "s3cr3t"},
+		ProviderConfig: cfg,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+func TestClientCredsTokenRequiresGrantTypeSupport(t *testing.T) {
+	c := newTestClient(t, &fakeHTTPClient{}, ProviderConfig{})
+
+	if _, err := c.ClientCredsToken([]string{"openid"}); err == nil {
+		t.Error("want an error when the provider does not advertise client_credentials")
+	}
+}
+
+func TestClientCredsTokenWithAudienceRequiresGrantTypeSupport(t *testing.T) {
+	c := newTestClient(t, &fakeHTTPClient{}, ProviderConfig{})
+
+	if _, err := c.ClientCredsTokenWithAudience([]string{"openid"}, "https://api.example.com"); err == nil {
+		t.Error("want an error when the provider does not advertise client_credentials")
+	}
+}
@@ -0,0 +1,115 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"testing"
+
+	"github.com/coreos/go-oidc/oauth2"
+)
+
+func TestChooseAuthMethodDefaultsToBasicWhenUnadvertised(t *testing.T) {
+	c := &Client{}
+
+	got, err := c.chooseAuthMethod(ProviderConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != oauth2.AuthMethodClientSecretBasic {
+		t.Errorf("want %s, got %s", oauth2.AuthMethodClientSecretBasic, got)
+	}
+}
+
+func TestChooseAuthMethodPrefersPrivateKeyJWTWhenConfigured(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating test key: %v", err)
+	}
+	c := &Client{signingKey: key}
+
+	cfg := ProviderConfig{TokenEndpointAuthMethodsSupported: []string{
+		oauth2.AuthMethodClientSecretBasic,
+		oauth2.AuthMethodClientSecretPost,
+		oauth2.AuthMethodPrivateKeyJWT,
+	}}
+
+	got, err := c.chooseAuthMethod(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != oauth2.AuthMethodPrivateKeyJWT {
+		t.Errorf("want %s even though it is listed last, got %s", oauth2.AuthMethodPrivateKeyJWT, got)
+	}
+}
+
+func TestChooseAuthMethodSkipsPrivateKeyJWTWithoutSigningKey(t *testing.T) {
+	c := &Client{}
+
+	cfg := ProviderConfig{TokenEndpointAuthMethodsSupported: []string{
+		oauth2.AuthMethodPrivateKeyJWT,
+		oauth2.AuthMethodClientSecretBasic,
+	}}
+
+	got, err := c.chooseAuthMethod(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != oauth2.AuthMethodClientSecretBasic {
+		t.Errorf("want fallback to %s, got %s", oauth2.AuthMethodClientSecretBasic, got)
+	}
+}
+
+func TestChooseAuthMethodSkipsTLSClientAuthWithoutTLSClientConfig(t *testing.T) {
+	c := &Client{}
+
+	cfg := ProviderConfig{TokenEndpointAuthMethodsSupported: []string{
+		oauth2.AuthMethodTLSClientAuth,
+		oauth2.AuthMethodClientSecretBasic,
+	}}
+
+	got, err := c.chooseAuthMethod(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != oauth2.AuthMethodClientSecretBasic {
+		t.Errorf("want fallback to %s, got %s", oauth2.AuthMethodClientSecretBasic, got)
+	}
+}
+
+func TestChooseAuthMethodNoOverlap(t *testing.T) {
+	c := &Client{}
+
+	cfg := ProviderConfig{TokenEndpointAuthMethodsSupported: []string{"unknown_method"}}
+
+	if _, err := c.chooseAuthMethod(cfg); err == nil {
+		t.Error("expected an error when no advertised method is supported")
+	}
+}
+
+func TestOAuthClientSelectsTLSClientAuthWhenConfigured(t *testing.T) {
+	c, err := NewClient(ClientConfig{
+		Credentials:     ClientCredentials{ID: "client-a"},
+		TLSClientConfig: &tls.Config{},
+		ProviderConfig: ProviderConfig{
+			AuthEndpoint:                      "https://idp.example.com/auth",
+			TokenEndpoint:                     "https://idp.example.com/token",
+			TokenEndpointAuthMethodsSupported: []string{oauth2.AuthMethodTLSClientAuth},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := c.OAuthClient(); err != nil {
+		t.Fatalf("OAuthClient: %v", err)
+	}
+
+	got, err := c.chooseAuthMethod(c.providerConfig.Get())
+	if err != nil {
+		t.Fatalf("chooseAuthMethod: %v", err)
+	}
+	if got != oauth2.AuthMethodTLSClientAuth {
+		t.Errorf("want %s selected, got %s", oauth2.AuthMethodTLSClientAuth, got)
+	}
+}
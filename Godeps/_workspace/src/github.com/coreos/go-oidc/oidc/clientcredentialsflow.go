@@ -0,0 +1,82 @@
+package oidc
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	phttp "github.com/coreos/go-oidc/http"
+	"github.com/coreos/go-oidc/jose"
+)
+
+// ClientCredentialsFlow is a convenience wrapper for the common
+// machine-to-machine case (CI systems, signing tools) of trading a
+// client's own credentials for an ID token: discover the issuer's token
+// endpoint, request a token with the client_credentials grant, and verify
+// the result against the issuer's JWKS. Callers that need more control,
+// such as reusing a previously-synced ProviderConfig, should build a
+// Client directly via NewClient and SyncProviderConfig instead.
+type ClientCredentialsFlow struct {
+	// Issuer is the provider's issuer URL; its discovery document is
+	// fetched from Issuer + "/.well-known/openid-configuration".
+	Issuer      string
+	Credentials ClientCredentials
+	Scopes      []string
+	Audience    string
+	HTTPClient  phttp.Client
+}
+
+// ClientCredentialsToken is the result of a successful client credentials
+// token request.
+type ClientCredentialsToken struct {
+	IDToken jose.JWT
+	Claims  jose.Claims
+	Subject string
+	Expiry  time.Time
+}
+
+// Token discovers f.Issuer's provider config, requests an ID token using
+// the client_credentials grant, and verifies it against the discovered
+// JWKS before returning it.
+func (f *ClientCredentialsFlow) Token() (*ClientCredentialsToken, error) {
+	hc := f.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	discoveryURL := strings.TrimSuffix(f.Issuer, "/") + "/.well-known/openid-configuration"
+	cfg, err := NewHTTPProviderConfigGetter(hc, discoveryURL).Get()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewClient(ClientConfig{
+		HTTPClient:     hc,
+		Credentials:    f.Credentials,
+		Scope:          f.Scopes,
+		ProviderConfig: cfg,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jwt, err := c.ClientCredsTokenWithAudience(f.Scopes, f.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := jwt.Claims()
+	if err != nil {
+		return nil, err
+	}
+
+	sub, _, _ := claims.StringClaim("sub")
+	exp, _ := claims.TimeClaim("exp")
+
+	return &ClientCredentialsToken{
+		IDToken: jwt,
+		Claims:  claims,
+		Subject: sub,
+		Expiry:  exp,
+	}, nil
+}
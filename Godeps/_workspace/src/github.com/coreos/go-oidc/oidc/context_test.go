@@ -0,0 +1,78 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/jose"
+)
+
+func TestAwaitJWTReturnsResultWhenContextNotDone(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	_, err := awaitJWT(context.Background(), func() (jose.JWT, error) {
+		return jose.JWT{}, wantErr
+	})
+	if err != wantErr {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestAwaitJWTReturnsCtxErrOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	_, err := awaitJWT(ctx, func() (jose.JWT, error) {
+		<-block
+		return jose.JWT{}, nil
+	})
+	if err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestAwaitJWTReturnsCtxErrOnDeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	_, err := awaitJWT(ctx, func() (jose.JWT, error) {
+		<-block
+		return jose.JWT{}, nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestAwaitErrReturnsResultWhenContextNotDone(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	err := awaitErr(context.Background(), func() error { return wantErr })
+	if err != wantErr {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestAwaitErrReturnsCtxErrOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	err := awaitErr(ctx, func() error {
+		<-block
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
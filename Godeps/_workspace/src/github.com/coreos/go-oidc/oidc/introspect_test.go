@@ -0,0 +1,98 @@
+package oidc
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIntrospectTokenParsesResponse(t *testing.T) {
+	hc := &fakeHTTPClient{body: `{
+		"active": true,
+		"scope": "openid email",
+		"client_id": "client-a",
+		"sub": "subject-1",
+		"exp": 1700000000
+	}`}
+	c := newTestClient(t, hc, ProviderConfig{
+		AuthEndpoint:          "https://idp.example.com/auth",
+		TokenEndpoint:         "https://idp.example.com/token",
+		IntrospectionEndpoint: "https://idp.example.com/introspect",
+	})
+
+	ir, err := c.IntrospectToken("opaque-token", "access_token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !ir.Active {
+		t.Error("want Active=true")
+	}
+	if ir.Scope != "openid email" {
+		t.Errorf("Scope = %q", ir.Scope)
+	}
+	if ir.Sub != "subject-1" {
+		t.Errorf("Sub = %q", ir.Sub)
+	}
+	if ir.Exp != 1700000000 {
+		t.Errorf("Exp = %d", ir.Exp)
+	}
+
+	if hc.lastURL != "https://idp.example.com/introspect" {
+		t.Errorf("posted to %q, want the introspection endpoint", hc.lastURL)
+	}
+	if got := hc.lastForm.Get("token"); got != "opaque-token" {
+		t.Errorf("token = %q", got)
+	}
+	if got := hc.lastForm.Get("token_type_hint"); got != "access_token" {
+		t.Errorf("token_type_hint = %q", got)
+	}
+}
+
+func TestIntrospectTokenRequiresEndpoint(t *testing.T) {
+	c := newTestClient(t, &fakeHTTPClient{}, ProviderConfig{})
+
+	if _, err := c.IntrospectToken("token", ""); err == nil {
+		t.Error("want an error when the provider has no introspection_endpoint")
+	}
+}
+
+func TestIntrospectTokenErrorsOnNonSuccessStatus(t *testing.T) {
+	hc := &fakeHTTPClient{status: http.StatusUnauthorized, body: `{"error":"invalid_client"}`}
+	c := newTestClient(t, hc, ProviderConfig{
+		AuthEndpoint:          "https://idp.example.com/auth",
+		TokenEndpoint:         "https://idp.example.com/token",
+		IntrospectionEndpoint: "https://idp.example.com/introspect",
+	})
+
+	if _, err := c.IntrospectToken("token", ""); err == nil {
+		t.Error("want an error on a non-2xx response")
+	}
+}
+
+func TestRevokeTokenPostsToRevocationEndpoint(t *testing.T) {
+	hc := &fakeHTTPClient{body: `{}`}
+	c := newTestClient(t, hc, ProviderConfig{
+		AuthEndpoint:       "https://idp.example.com/auth",
+		TokenEndpoint:      "https://idp.example.com/token",
+		RevocationEndpoint: "https://idp.example.com/revoke",
+	})
+
+	if err := c.RevokeToken("opaque-token", "refresh_token"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hc.lastURL != "https://idp.example.com/revoke" {
+		t.Errorf("posted to %q, want the revocation endpoint", hc.lastURL)
+	}
+	if got := hc.lastForm.Get("token"); got != "opaque-token" {
+		t.Errorf("token = %q", got)
+	}
+}
+
+func TestRevokeTokenRequiresEndpoint(t *testing.T) {
+	c := newTestClient(t, &fakeHTTPClient{}, ProviderConfig{})
+
+	if err := c.RevokeToken("token", ""); err == nil {
+		t.Error("want an error when the provider has no revocation_endpoint")
+	}
+}
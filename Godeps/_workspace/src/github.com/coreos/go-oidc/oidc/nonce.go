@@ -0,0 +1,162 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/jose"
+	"github.com/coreos/go-oidc/oauth2"
+)
+
+// NonceStore tracks nonce values generated at authorization-request time
+// so a later VerifyJWTWithNonce can confirm an ID token's nonce claim
+// matches what this client actually sent, and that the same token is not
+// accepted twice (replay protection).
+type NonceStore interface {
+	// Add records nonce as valid for ttl.
+	Add(nonce string, ttl time.Duration)
+	// Claim reports whether nonce was previously Add-ed and has not
+	// expired or already been claimed. A successful claim consumes the
+	// nonce, so replaying the same ID token fails on its second
+	// verification.
+	Claim(nonce string) bool
+}
+
+// NewNonceStore returns an in-memory, single-use NonceStore. It is safe
+// for concurrent use but, being in-memory, is only suitable for a single
+// process.
+func NewNonceStore() NonceStore {
+	return &memNonceStore{entries: make(map[string]time.Time)}
+}
+
+type memNonceStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func (s *memNonceStore) Add(nonce string, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[nonce] = time.Now().UTC().Add(ttl)
+}
+
+func (s *memNonceStore) Claim(nonce string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exp, ok := s.entries[nonce]
+	if !ok {
+		return false
+	}
+	delete(s.entries, nonce)
+
+	return time.Now().UTC().Before(exp)
+}
+
+// GenerateNonce returns a cryptographically random value suitable for use
+// as either the OIDC "nonce" or the OAuth2 "state" parameter.
+func GenerateNonce() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NonceAndState returns the "nonce" and "state" values to attach to an
+// authorization request, generating a fresh random value for either
+// argument left empty.
+func NonceAndState(nonce, state string) (actualNonce, actualState string, err error) {
+	actualNonce, actualState = nonce, state
+
+	if actualNonce == "" {
+		if actualNonce, err = GenerateNonce(); err != nil {
+			return "", "", err
+		}
+	}
+	if actualState == "" {
+		if actualState, err = GenerateNonce(); err != nil {
+			return "", "", err
+		}
+	}
+
+	return actualNonce, actualState, nil
+}
+
+// AuthCodeURLWithNonce returns an authorization URL with nonce and state
+// attached, generating a fresh random value for either argument left
+// empty, and records the nonce in store for ttl so a later
+// ExchangeAuthCodeWithNonce/VerifyJWTWithNonce call can validate it.
+func (c *Client) AuthCodeURLWithNonce(nonce, state string, store NonceStore, ttl time.Duration) (authURL, actualNonce, actualState string, err error) {
+	actualNonce, actualState, err = NonceAndState(nonce, state)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	authURL, err = c.AuthCodeURL(actualState, oauth2.Param{Key: "nonce", Value: actualNonce})
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if store != nil {
+		store.Add(actualNonce, ttl)
+	}
+
+	return authURL, actualNonce, actualState, nil
+}
+
+// VerifyJWTWithNonce is identical to Client.VerifyJWT, but additionally
+// rejects jwt unless store reports that nonce was issued by this client
+// and not yet claimed, and jwt's "nonce" claim equals it.
+func (c *Client) VerifyJWTWithNonce(jwt jose.JWT, nonce string, store NonceStore) error {
+	if err := c.VerifyJWT(jwt); err != nil {
+		return err
+	}
+
+	claims, err := jwt.Claims()
+	if err != nil {
+		return err
+	}
+
+	got, ok, err := claims.StringClaim("nonce")
+	if err != nil {
+		return err
+	}
+	if !ok || got == "" {
+		return errors.New("oidc: jwt missing nonce claim")
+	}
+	if got != nonce {
+		return errors.New("oidc: jwt nonce claim does not match expected value")
+	}
+
+	if store != nil && !store.Claim(nonce) {
+		return errors.New("oidc: nonce not recognized or already used")
+	}
+
+	return nil
+}
+
+// ExchangeAuthCodeWithNonce is identical to ExchangeAuthCode, but also
+// verifies the returned ID token's nonce claim against expectedNonce using
+// store, rejecting forged or replayed tokens.
+func (c *Client) ExchangeAuthCodeWithNonce(code, expectedNonce string, store NonceStore) (jose.JWT, error) {
+	oac, err := c.OAuthClient()
+	if err != nil {
+		return jose.JWT{}, err
+	}
+
+	t, err := oac.RequestToken(oauth2.GrantTypeAuthCode, code)
+	if err != nil {
+		return jose.JWT{}, err
+	}
+
+	jwt, err := jose.ParseJWT(t.IDToken)
+	if err != nil {
+		return jose.JWT{}, err
+	}
+
+	return jwt, c.VerifyJWTWithNonce(jwt, expectedNonce, store)
+}
@@ -0,0 +1,81 @@
+package oidc
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestMemNonceStoreClaimIsSingleUse(t *testing.T) {
+	s := NewNonceStore()
+	s.Add("n1", time.Minute)
+
+	if !s.Claim("n1") {
+		t.Fatal("first claim of a valid nonce should succeed")
+	}
+	if s.Claim("n1") {
+		t.Fatal("second claim of the same nonce should fail (replay)")
+	}
+}
+
+func TestMemNonceStoreClaimRejectsExpired(t *testing.T) {
+	s := NewNonceStore()
+	s.Add("n1", -time.Minute)
+
+	if s.Claim("n1") {
+		t.Fatal("claim of an expired nonce should fail")
+	}
+}
+
+func TestMemNonceStoreClaimRejectsUnknown(t *testing.T) {
+	s := NewNonceStore()
+
+	if s.Claim("never-added") {
+		t.Fatal("claim of a nonce that was never Add-ed should fail")
+	}
+}
+
+func TestNonceAndStateGeneratesMissingValues(t *testing.T) {
+	nonce, state, err := NonceAndState("fixed-nonce", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nonce != "fixed-nonce" {
+		t.Errorf("want the provided nonce preserved, got %q", nonce)
+	}
+	if state == "" {
+		t.Error("want a generated state value")
+	}
+}
+
+func TestAuthCodeURLWithNonceAttachesNonceAndRecordsIt(t *testing.T) {
+	c := &Client{
+		redirectURL: "https://example.com/callback",
+		providerConfig: newProviderConfigRepo(ProviderConfig{
+			AuthEndpoint:  "https://idp.example.com/auth",
+			TokenEndpoint: "https://idp.example.com/token",
+		}),
+	}
+	store := NewNonceStore()
+
+	authURL, nonce, state, err := c.AuthCodeURLWithNonce("", "", store, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parsing returned auth URL: %v", err)
+	}
+	q := u.Query()
+	if got := q.Get("nonce"); got != nonce {
+		t.Errorf("nonce in URL = %q, want %q", got, nonce)
+	}
+	if got := q.Get("state"); got != state {
+		t.Errorf("state in URL = %q, want %q", got, state)
+	}
+
+	if !store.Claim(nonce) {
+		t.Error("want the generated nonce to have been recorded in store")
+	}
+}
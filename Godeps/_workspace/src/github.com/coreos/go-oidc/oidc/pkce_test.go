@@ -0,0 +1,66 @@
+package oidc
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestCodeChallengeS256(t *testing.T) {
+	// RFC 7636 Appendix B worked example.
+	const (
+		verifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+		challenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	)
+
+	if got := CodeChallengeS256(verifier); got != challenge {
+		t.Errorf("CodeChallengeS256(%q) = %q, want %q", verifier, got, challenge)
+	}
+}
+
+func TestGenerateCodeVerifierUnique(t *testing.T) {
+	a, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a == b {
+		t.Error("two generated verifiers should not be equal")
+	}
+}
+
+func TestAuthCodeURLWithPKCEAttachesChallenge(t *testing.T) {
+	c := &Client{
+		redirectURL: "https://example.com/callback",
+		providerConfig: newProviderConfigRepo(ProviderConfig{
+			AuthEndpoint:  "https://idp.example.com/auth",
+			TokenEndpoint: "https://idp.example.com/token",
+		}),
+	}
+
+	authURL, verifier, err := c.AuthCodeURLWithPKCE("the-state")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verifier == "" {
+		t.Fatal("expected a non-empty verifier")
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parsing returned auth URL: %v", err)
+	}
+
+	q := u.Query()
+	if got := q.Get("code_challenge"); got != CodeChallengeS256(verifier) {
+		t.Errorf("code_challenge = %q, want %q", got, CodeChallengeS256(verifier))
+	}
+	if got := q.Get("code_challenge_method"); got != CodeChallengeMethodS256 {
+		t.Errorf("code_challenge_method = %q, want %q", got, CodeChallengeMethodS256)
+	}
+	if got := q.Get("state"); got != "the-state" {
+		t.Errorf("state = %q, want %q", got, "the-state")
+	}
+}
@@ -0,0 +1,230 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	phttp "github.com/coreos/go-oidc/http"
+	"github.com/coreos/go-oidc/oauth2"
+)
+
+// defaultProviderConfigTTL bounds how long a ProviderConfig fetched over
+// HTTP is considered fresh when the discovery response carries no
+// explicit expiry, and doubles as the syncer's retry interval after a
+// failed fetch.
+const defaultProviderConfigTTL = 12 * time.Hour
+
+// ProviderConfig is an OpenID Connect provider's discovery document:
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type ProviderConfig struct {
+	Issuer        string `json:"issuer"`
+	AuthEndpoint  string `json:"authorization_endpoint"`
+	TokenEndpoint string `json:"token_endpoint"`
+	KeysEndpoint  string `json:"jwks_uri"`
+
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	GrantTypesSupported               []string `json:"grant_types_supported,omitempty"`
+
+	// IntrospectionEndpoint and RevocationEndpoint are optional; a
+	// provider that omits them from its discovery document does not
+	// support RFC 7662 introspection or RFC 7009 revocation, respectively.
+	IntrospectionEndpoint string `json:"introspection_endpoint,omitempty"`
+	RevocationEndpoint    string `json:"revocation_endpoint,omitempty"`
+
+	// ExpiresAt is not part of the discovery document; it is set locally
+	// to bound how long a fetched ProviderConfig is trusted before a
+	// ProviderConfigSyncer re-fetches it.
+	ExpiresAt time.Time `json:"-"`
+}
+
+// Empty reports whether c has no Issuer, as is the case for the zero
+// value before any provider config has been set.
+func (c ProviderConfig) Empty() bool {
+	return c.Issuer == ""
+}
+
+// SupportsGrantType reports whether the provider advertises support for
+// grantType. Per the OIDC discovery spec, a provider that omits
+// grant_types_supported entirely is assumed to support only the OAuth2
+// default, authorization_code.
+func (c ProviderConfig) SupportsGrantType(grantType string) bool {
+	if len(c.GrantTypesSupported) == 0 {
+		return grantType == oauth2.GrantTypeAuthCode
+	}
+
+	for _, gt := range c.GrantTypesSupported {
+		if gt == grantType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProviderConfigGetter retrieves a ProviderConfig from some source: an
+// HTTP discovery endpoint, a file on disk, or a static, pre-fetched value.
+type ProviderConfigGetter interface {
+	Get() (ProviderConfig, error)
+}
+
+// ProviderConfigSetter receives ProviderConfig updates from a
+// ProviderConfigSyncer.
+type ProviderConfigSetter interface {
+	Set(ProviderConfig) error
+}
+
+// httpProviderConfigGetter fetches a ProviderConfig from a provider's
+// well-known discovery endpoint.
+type httpProviderConfigGetter struct {
+	hc  phttp.Client
+	url string
+}
+
+// NewHTTPProviderConfigGetter returns a ProviderConfigGetter that fetches
+// the provider's discovery document from discoveryURL over HTTP.
+func NewHTTPProviderConfigGetter(hc phttp.Client, discoveryURL string) ProviderConfigGetter {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &httpProviderConfigGetter{hc: hc, url: discoveryURL}
+}
+
+func (g *httpProviderConfigGetter) Get() (ProviderConfig, error) {
+	var cfg ProviderConfig
+
+	req, err := http.NewRequest("GET", g.url, nil)
+	if err != nil {
+		return cfg, err
+	}
+
+	resp, err := g.hc.Do(req)
+	if err != nil {
+		return cfg, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return cfg, fmt.Errorf("oidc: discovery request to %s returned %s", g.url, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(body, &cfg); err != nil {
+		return cfg, err
+	}
+
+	cfg.ExpiresAt = time.Now().UTC().Add(defaultProviderConfigTTL)
+	return cfg, nil
+}
+
+// ProviderConfigSyncer periodically re-fetches a ProviderConfig from a
+// ProviderConfigGetter and writes it to a ProviderConfigSetter, so callers
+// always have a reasonably fresh copy without fetching on every use.
+type ProviderConfigSyncer struct {
+	from ProviderConfigGetter
+	to   ProviderConfigSetter
+
+	initialSyncDone chan struct{}
+	initialSyncErr  error
+}
+
+// NewProviderConfigSyncer returns a ProviderConfigSyncer that syncs from
+// from to to.
+func NewProviderConfigSyncer(from ProviderConfigGetter, to ProviderConfigSetter) *ProviderConfigSyncer {
+	return &ProviderConfigSyncer{
+		from:            from,
+		to:              to,
+		initialSyncDone: make(chan struct{}),
+	}
+}
+
+// Run starts the syncer's background fetch loop, returning a channel that
+// stops it when closed.
+func (s *ProviderConfigSyncer) Run() chan struct{} {
+	stop := make(chan struct{})
+	go s.run(stop)
+	return stop
+}
+
+// RunContext is Run, but the background loop also stops when ctx is
+// done, in addition to the returned channel being closed. Unlike
+// WaitUntilInitialSyncContext (which only bounds a caller's wait on a
+// fetch already running in the background), this genuinely ends the
+// steady-state polling loop early — no further fetches run once ctx is
+// done. Once ctx is done the returned channel is closed by the syncer
+// itself. A close made through closeStop tolerates an already-closed
+// channel; a plain close of the returned channel after ctx is done panics.
+func (s *ProviderConfigSyncer) RunContext(ctx context.Context) chan struct{} {
+	stop := make(chan struct{})
+	go s.run(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			closeStop(stop)
+		case <-stop:
+		}
+	}()
+	return stop
+}
+
+// closeStop closes stop, tolerating the case where it is already closed
+// (e.g. the caller closed it directly at the same moment ctx became
+// done).
+func closeStop(stop chan struct{}) {
+	defer func() { recover() }()
+	close(stop)
+}
+
+func (s *ProviderConfigSyncer) run(stop chan struct{}) {
+	first := true
+
+	for {
+		cfg, err := s.from.Get()
+		if err == nil {
+			err = s.to.Set(cfg)
+		}
+		s.initialSyncErr = err
+
+		if first {
+			close(s.initialSyncDone)
+			first = false
+		}
+
+		wait := defaultProviderConfigTTL
+		if err == nil && !cfg.ExpiresAt.IsZero() {
+			if d := cfg.ExpiresAt.Sub(time.Now().UTC()); d > 0 {
+				wait = d
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// WaitUntilInitialSync blocks until the syncer's first fetch attempt has
+// completed, successfully or not.
+func (s *ProviderConfigSyncer) WaitUntilInitialSync() {
+	<-s.initialSyncDone
+}
+
+// WaitUntilInitialSyncContext is WaitUntilInitialSync, but returns
+// ctx.Err() if ctx is done before the initial fetch completes, instead of
+// blocking indefinitely against an unreachable provider.
+func (s *ProviderConfigSyncer) WaitUntilInitialSyncContext(ctx context.Context) error {
+	select {
+	case <-s.initialSyncDone:
+		return s.initialSyncErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
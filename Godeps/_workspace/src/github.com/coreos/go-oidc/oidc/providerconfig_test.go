@@ -0,0 +1,73 @@
+package oidc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestProviderConfigEmpty(t *testing.T) {
+	if !(ProviderConfig{}).Empty() {
+		t.Error("want the zero value to be Empty")
+	}
+	if (ProviderConfig{Issuer: "https://idp.example.com"}).Empty() {
+		t.Error("want a config with an Issuer to not be Empty")
+	}
+}
+
+func TestProviderConfigSupportsGrantTypeDefaultsToAuthCode(t *testing.T) {
+	cfg := ProviderConfig{}
+
+	if !cfg.SupportsGrantType("authorization_code") {
+		t.Error("want authorization_code supported when grant_types_supported is omitted")
+	}
+	if cfg.SupportsGrantType("client_credentials") {
+		t.Error("want client_credentials unsupported when grant_types_supported is omitted")
+	}
+}
+
+func TestProviderConfigSupportsGrantTypeExplicitList(t *testing.T) {
+	cfg := ProviderConfig{GrantTypesSupported: []string{"authorization_code", "client_credentials"}}
+
+	if !cfg.SupportsGrantType("client_credentials") {
+		t.Error("want client_credentials supported when explicitly advertised")
+	}
+	if cfg.SupportsGrantType("refresh_token") {
+		t.Error("want refresh_token unsupported when not advertised")
+	}
+}
+
+// staticConfigGetter always returns cfg, for use as a ProviderConfigSyncer
+// source in tests.
+type staticConfigGetter struct{ cfg ProviderConfig }
+
+func (g staticConfigGetter) Get() (ProviderConfig, error) { return g.cfg, nil }
+
+func TestRunContextStopsPollingWhenCtxDone(t *testing.T) {
+	to := newProviderConfigRepo(ProviderConfig{})
+	s := NewProviderConfigSyncer(staticConfigGetter{ProviderConfig{Issuer: "https://idp.example.com"}}, to)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := s.RunContext(ctx)
+	s.WaitUntilInitialSync()
+
+	cancel()
+
+	select {
+	case <-stop:
+	case <-time.After(time.Second):
+		t.Fatal("want RunContext's loop to stop once ctx is done")
+	}
+}
+
+func TestRunContextToleratesCloseRacingCtxDone(t *testing.T) {
+	to := newProviderConfigRepo(ProviderConfig{})
+	s := NewProviderConfigSyncer(staticConfigGetter{ProviderConfig{Issuer: "https://idp.example.com"}}, to)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := s.RunContext(ctx)
+	s.WaitUntilInitialSync()
+
+	cancel()
+	closeStop(stop) // simulate the caller also closing it; must not panic
+}
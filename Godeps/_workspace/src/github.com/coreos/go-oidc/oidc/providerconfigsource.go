@@ -0,0 +1,54 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// fileProviderConfigGetter reads a ProviderConfig from a JSON file on
+// disk, re-reading it on every Get so an operator can roll a new
+// discovery document onto disk without restarting the process.
+type fileProviderConfigGetter struct {
+	path string
+}
+
+// NewFileProviderConfigGetter returns a ProviderConfigGetter that reads
+// the provider's discovery document from the JSON file at path, rather
+// than fetching it over HTTP. This is useful for air-gapped deployments
+// or for pinning a specific discovery document.
+func NewFileProviderConfigGetter(path string) ProviderConfigGetter {
+	return &fileProviderConfigGetter{path: path}
+}
+
+func (g *fileProviderConfigGetter) Get() (ProviderConfig, error) {
+	var cfg ProviderConfig
+
+	b, err := ioutil.ReadFile(g.path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading provider config from %s: %v", g.path, err)
+	}
+
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing provider config from %s: %v", g.path, err)
+	}
+
+	return cfg, nil
+}
+
+// staticProviderConfigGetter always returns the ProviderConfig it was
+// constructed with.
+type staticProviderConfigGetter struct {
+	cfg ProviderConfig
+}
+
+// NewStaticProviderConfigGetter returns a ProviderConfigGetter that always
+// returns cfg, for callers that already have a trusted, pre-fetched
+// provider config and don't want a background syncer re-fetching it.
+func NewStaticProviderConfigGetter(cfg ProviderConfig) ProviderConfigGetter {
+	return &staticProviderConfigGetter{cfg: cfg}
+}
+
+func (g *staticProviderConfigGetter) Get() (ProviderConfig, error) {
+	return g.cfg, nil
+}
@@ -0,0 +1,76 @@
+package oidc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticProviderConfigGetterReturnsConfig(t *testing.T) {
+	want := ProviderConfig{Issuer: "https://idp.example.com"}
+
+	got, err := NewStaticProviderConfigGetter(want).Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Issuer != want.Issuer {
+		t.Errorf("Issuer = %q, want %q", got.Issuer, want.Issuer)
+	}
+}
+
+func TestFileProviderConfigGetterReadsJSON(t *testing.T) {
+	cfg := ProviderConfig{
+		Issuer:        "https://idp.example.com",
+		AuthEndpoint:  "https://idp.example.com/auth",
+		TokenEndpoint: "https://idp.example.com/token",
+	}
+
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshaling config: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "oidc-provider-config")
+	if err != nil {
+		t.Fatalf("creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "provider-config.json")
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("writing config file: %v", err)
+	}
+
+	got, err := NewFileProviderConfigGetter(path).Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Issuer != cfg.Issuer || got.AuthEndpoint != cfg.AuthEndpoint || got.TokenEndpoint != cfg.TokenEndpoint {
+		t.Errorf("got %+v, want %+v", got, cfg)
+	}
+}
+
+func TestFileProviderConfigGetterMissingFile(t *testing.T) {
+	if _, err := NewFileProviderConfigGetter("/does/not/exist.json").Get(); err == nil {
+		t.Error("want an error for a file that does not exist")
+	}
+}
+
+func TestFileProviderConfigGetterInvalidJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oidc-provider-config")
+	if err != nil {
+		t.Fatalf("creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "provider-config.json")
+	if err := ioutil.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatalf("writing config file: %v", err)
+	}
+
+	if _, err := NewFileProviderConfigGetter(path).Get(); err == nil {
+		t.Error("want an error for invalid JSON")
+	}
+}